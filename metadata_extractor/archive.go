@@ -0,0 +1,196 @@
+package main
+
+import (
+    "archive/tar"
+    "archive/zip"
+    "bytes"
+    "compress/bzip2"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "log"
+    "path/filepath"
+    "strings"
+    "sync/atomic"
+)
+
+// archiveOptions carries the decompression-bomb guards requested on the
+// command line through a recursive processArchive walk.
+type archiveOptions struct {
+    MaxDepth      int
+    MaxEntrySize  int64
+    MaxTotalSize  int64
+    totalSize     *atomic.Int64 // shared across the whole recursive walk
+}
+
+// archiveExtensions lists the extensions processArchive knows how to open
+// itself, as opposed to ones that would need a pluggable external backend
+// (7z, rar) that this build doesn't ship.
+var archiveExtensions = map[string]bool{
+    "zip": true, "tar": true, "tgz": true, "tar.gz": true, "tbz2": true, "tar.bz2": true,
+}
+
+// isArchive reports whether name looks like an archive processArchive can
+// recurse into, based on its extension.
+func isArchive(name string) bool {
+    lower := strings.ToLower(name)
+    for ext := range archiveExtensions {
+        if strings.HasSuffix(lower, "."+ext) {
+            return true
+        }
+    }
+    return false
+}
+
+// processArchive walks an archive's entries, recursing into nested
+// archives up to opts.MaxDepth, and submits every non-archive entry to
+// pool as a job. Entries that would escape the archive root (absolute
+// paths, "..") or blow past the configured size limits are recorded as
+// diagnostics and skipped rather than processed.
+func processArchive(name string, raw []byte, depth int, opts archiveOptions, pool *workerPool, diags *diagnosticCollector) {
+    log.Printf("Processing archive %s (depth %d)\n", name, depth)
+
+    if depth > opts.MaxDepth {
+        diags.add(name, "archive", fmt.Errorf("max archive depth %d exceeded", opts.MaxDepth), SeverityError)
+        return
+    }
+
+    lower := strings.ToLower(name)
+    switch {
+    case strings.HasSuffix(lower, ".zip"):
+        walkZip(name, raw, depth, opts, pool, diags)
+    case strings.HasSuffix(lower, ".tar"):
+        walkTar(name, bytes.NewReader(raw), depth, opts, pool, diags)
+    case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+        gzr, err := gzip.NewReader(bytes.NewReader(raw))
+        if err != nil {
+            diags.add(name, "archive", fmt.Errorf("failed to open gzip stream: %w", err), SeverityError)
+            return
+        }
+        defer gzr.Close()
+        walkTar(name, gzr, depth, opts, pool, diags)
+    case strings.HasSuffix(lower, ".tar.bz2"), strings.HasSuffix(lower, ".tbz2"):
+        walkTar(name, bzip2.NewReader(bytes.NewReader(raw)), depth, opts, pool, diags)
+    default:
+        diags.add(name, "archive", fmt.Errorf("no archive backend registered for %s", filepath.Ext(name)), SeverityWarning)
+    }
+}
+
+func walkZip(name string, raw []byte, depth int, opts archiveOptions, pool *workerPool, diags *diagnosticCollector) {
+    zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+    if err != nil {
+        diags.add(name, "archive", fmt.Errorf("failed to open ZIP archive: %w", err), SeverityError)
+        return
+    }
+
+    for _, f := range zr.File {
+        if f.FileInfo().IsDir() {
+            continue
+        }
+        entryName := name + "/" + f.Name
+        if !isSafeEntryPath(f.Name) {
+            diags.add(entryName, "archive", fmt.Errorf("refusing unsafe entry path"), SeverityError)
+            continue
+        }
+        if int64(f.UncompressedSize64) > opts.MaxEntrySize {
+            diags.add(entryName, "archive", fmt.Errorf("entry exceeds max-entry-size (%d bytes)", opts.MaxEntrySize), SeverityError)
+            continue
+        }
+
+        rc, err := f.Open()
+        if err != nil {
+            diags.add(entryName, "archive", fmt.Errorf("failed to open entry: %w", err), SeverityError)
+            continue
+        }
+        entryRaw, err := readEntry(rc, opts)
+        rc.Close()
+        if err != nil {
+            diags.add(entryName, "archive", err, SeverityError)
+            continue
+        }
+
+        if !dispatchArchiveEntry(entryName, f.Name, entryRaw, depth, opts, pool, diags) {
+            return
+        }
+    }
+}
+
+func walkTar(name string, r io.Reader, depth int, opts archiveOptions, pool *workerPool, diags *diagnosticCollector) {
+    tr := tar.NewReader(r)
+    for {
+        hdr, err := tr.Next()
+        if err == io.EOF {
+            return
+        }
+        if err != nil {
+            diags.add(name, "archive", fmt.Errorf("failed to read tar entry: %w", err), SeverityError)
+            return
+        }
+        if hdr.Typeflag != tar.TypeReg {
+            continue
+        }
+
+        entryName := name + "/" + hdr.Name
+        if !isSafeEntryPath(hdr.Name) {
+            diags.add(entryName, "archive", fmt.Errorf("refusing unsafe entry path"), SeverityError)
+            continue
+        }
+        if hdr.Size > opts.MaxEntrySize {
+            diags.add(entryName, "archive", fmt.Errorf("entry exceeds max-entry-size (%d bytes)", opts.MaxEntrySize), SeverityError)
+            continue
+        }
+
+        entryRaw, err := readEntry(io.LimitReader(tr, hdr.Size), opts)
+        if err != nil {
+            diags.add(entryName, "archive", err, SeverityError)
+            continue
+        }
+
+        if !dispatchArchiveEntry(entryName, hdr.Name, entryRaw, depth, opts, pool, diags) {
+            return
+        }
+    }
+}
+
+// readEntry copies an archive entry into memory, capping the read at
+// MaxEntrySize+1 bytes so a ZIP entry that understates its own
+// uncompressed size in the central directory (a classic decompression
+// bomb) can't be used to bypass --max-entry-size, and enforcing
+// MaxTotalSize across the whole recursive walk.
+func readEntry(r io.Reader, opts archiveOptions) ([]byte, error) {
+    raw, err := io.ReadAll(io.LimitReader(r, opts.MaxEntrySize+1))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read entry: %w", err)
+    }
+    if int64(len(raw)) > opts.MaxEntrySize {
+        return nil, fmt.Errorf("entry exceeds max-entry-size (%d bytes)", opts.MaxEntrySize)
+    }
+    if opts.totalSize.Add(int64(len(raw))) > opts.MaxTotalSize {
+        return nil, fmt.Errorf("max-total-size (%d bytes) exceeded", opts.MaxTotalSize)
+    }
+    return raw, nil
+}
+
+// dispatchArchiveEntry either recurses into a nested archive or submits
+// the entry to the worker pool as a regular extraction job. It returns
+// false once the pool has been cancelled (fail-fast tripped), signaling
+// the caller to stop walking further entries.
+func dispatchArchiveEntry(entryName, baseName string, raw []byte, depth int, opts archiveOptions, pool *workerPool, diags *diagnosticCollector) bool {
+    if isArchive(baseName) {
+        processArchive(entryName, raw, depth+1, opts, pool, diags)
+        return pool.ctx.Err() == nil
+    }
+
+    ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(baseName), "."))
+    return pool.submit(job{Name: entryName, Ext: ext, Raw: raw})
+}
+
+// isSafeEntryPath rejects archive entries that would write (or, here,
+// resolve) outside of the archive root via an absolute path or "..".
+// filepath.ToSlash is a no-op on non-Windows builds, so entries are
+// normalized by hand rather than relying on it to catch "..\"-style
+// traversal on a Linux build.
+func isSafeEntryPath(name string) bool {
+    cleaned := filepath.Clean(strings.ReplaceAll(name, "\\", "/"))
+    return !filepath.IsAbs(cleaned) && cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestIsSafeEntryPath(t *testing.T) {
+    cases := []struct {
+        name string
+        safe bool
+    }{
+        {"file.txt", true},
+        {"dir/file.txt", true},
+        {"/etc/passwd", false},
+        {"../escape.txt", false},
+        {"dir/../../escape.txt", false},
+        {`..\..\etc\passwd`, false},
+        {`dir\file.txt`, true},
+    }
+
+    for _, c := range cases {
+        if got := isSafeEntryPath(c.name); got != c.safe {
+            t.Errorf("isSafeEntryPath(%q) = %v, want %v", c.name, got, c.safe)
+        }
+    }
+}
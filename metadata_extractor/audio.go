@@ -0,0 +1,171 @@
+package main
+
+import (
+    "encoding/binary"
+    "fmt"
+    "io"
+    "log"
+    "strings"
+)
+
+func init() {
+    Register(&audioExtractor{})
+}
+
+// audioExtractor reads ID3v1/ID3v2 tags from MP3 files and Vorbis
+// comments from FLAC files.
+type audioExtractor struct{}
+
+func (audioExtractor) Extensions() []string { return []string{"mp3", "flac"} }
+func (audioExtractor) Type() string         { return "Audio" }
+
+func (audioExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting audio tags from %s\n", name)
+
+    raw, err := io.ReadAll(r)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read audio file: %w", err)
+    }
+
+    if len(raw) >= 4 && string(raw[:4]) == "fLaC" {
+        return parseFlacComments(raw)
+    }
+
+    data := make(map[string]interface{})
+    if id3v2, err := parseID3v2(raw); err == nil {
+        for k, v := range id3v2 {
+            data[k] = v
+        }
+    }
+    if id3v1, err := parseID3v1(raw); err == nil {
+        for k, v := range id3v1 {
+            if _, exists := data[k]; !exists {
+                data[k] = v
+            }
+        }
+    }
+
+    if len(data) == 0 {
+        return nil, fmt.Errorf("no ID3 tag found")
+    }
+    return data, nil
+}
+
+// parseID3v1 reads the fixed-layout 128 byte tag at the end of the file.
+func parseID3v1(raw []byte) (map[string]interface{}, error) {
+    if len(raw) < 128 || string(raw[len(raw)-128:len(raw)-125]) != "TAG" {
+        return nil, fmt.Errorf("no ID3v1 tag present")
+    }
+    tag := raw[len(raw)-128:]
+    trim := func(b []byte) string { return strings.TrimRight(string(b), "\x00 ") }
+
+    return map[string]interface{}{
+        "Title":   trim(tag[3:33]),
+        "Artist":  trim(tag[33:63]),
+        "Album":   trim(tag[63:93]),
+        "Year":    trim(tag[93:97]),
+        "Comment": trim(tag[97:125]),
+    }, nil
+}
+
+// parseID3v2 decodes the text frames of an ID3v2.3/2.4 tag at the start
+// of the file.
+func parseID3v2(raw []byte) (map[string]interface{}, error) {
+    if len(raw) < 10 || string(raw[:3]) != "ID3" {
+        return nil, fmt.Errorf("no ID3v2 tag present")
+    }
+
+    size := syncSafeUint(raw[6:10])
+    if 10+size > len(raw) {
+        return nil, fmt.Errorf("id3v2 tag size exceeds file length")
+    }
+    frames := raw[10 : 10+size]
+
+    data := make(map[string]interface{})
+    for len(frames) >= 10 {
+        id := string(frames[0:4])
+        if id == "\x00\x00\x00\x00" {
+            break
+        }
+        frameSize := int(binary.BigEndian.Uint32(frames[4:8]))
+        if 10+frameSize > len(frames) || frameSize < 0 {
+            break
+        }
+        content := frames[10 : 10+frameSize]
+        if strings.HasPrefix(id, "T") && len(content) > 1 {
+            // first byte is the text encoding; skip it for the common
+            // ISO-8859-1/UTF-8 case.
+            data[id] = strings.TrimRight(string(content[1:]), "\x00")
+        }
+        frames = frames[10+frameSize:]
+    }
+    return data, nil
+}
+
+// syncSafeUint decodes a 4 byte ID3v2 "synchsafe" integer, where only the
+// lower 7 bits of each byte are significant.
+func syncSafeUint(b []byte) int {
+    return int(b[0])<<21 | int(b[1])<<14 | int(b[2])<<7 | int(b[3])
+}
+
+// parseFlacComments extracts the VORBIS_COMMENT metadata block from a
+// FLAC stream.
+func parseFlacComments(raw []byte) (interface{}, error) {
+    pos := 4 // past "fLaC"
+    for pos+4 <= len(raw) {
+        header := raw[pos]
+        blockType := header & 0x7f
+        last := header&0x80 != 0
+        length := int(raw[pos+1])<<16 | int(raw[pos+2])<<8 | int(raw[pos+3])
+        pos += 4
+        if pos+length > len(raw) {
+            break
+        }
+        block := raw[pos : pos+length]
+
+        if blockType == 4 { // VORBIS_COMMENT
+            return decodeVorbisComment(block)
+        }
+
+        pos += length
+        if last {
+            break
+        }
+    }
+    return nil, fmt.Errorf("no VORBIS_COMMENT block found")
+}
+
+func decodeVorbisComment(block []byte) (map[string]interface{}, error) {
+    if len(block) < 4 {
+        return nil, fmt.Errorf("vorbis comment block too short")
+    }
+    offset := 0
+    vendorLen := int(binary.LittleEndian.Uint32(block[offset:]))
+    offset += 4
+    if offset+vendorLen > len(block) {
+        return nil, fmt.Errorf("vorbis comment vendor string truncated")
+    }
+    data := map[string]interface{}{"VENDOR": string(block[offset : offset+vendorLen])}
+    offset += vendorLen
+
+    if offset+4 > len(block) {
+        return data, nil
+    }
+    count := int(binary.LittleEndian.Uint32(block[offset:]))
+    offset += 4
+
+    for i := 0; i < count && offset+4 <= len(block); i++ {
+        commentLen := int(binary.LittleEndian.Uint32(block[offset:]))
+        offset += 4
+        if offset+commentLen > len(block) {
+            break
+        }
+        comment := string(block[offset : offset+commentLen])
+        offset += commentLen
+
+        if key, value, found := strings.Cut(comment, "="); found {
+            data[strings.ToUpper(key)] = value
+        }
+    }
+    return data, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+    "bytes"
+    "encoding/binary"
+    "testing"
+)
+
+func TestParseID3v1(t *testing.T) {
+    tag := make([]byte, 128)
+    copy(tag[0:3], "TAG")
+    copy(tag[3:33], "Title")
+    copy(tag[33:63], "Artist")
+    copy(tag[63:93], "Album")
+    copy(tag[93:97], "1999")
+
+    raw := append([]byte("junk before the tag"), tag...)
+    got, err := parseID3v1(raw)
+    if err != nil {
+        t.Fatalf("parseID3v1: %v", err)
+    }
+
+    want := map[string]interface{}{"Title": "Title", "Artist": "Artist", "Album": "Album", "Year": "1999", "Comment": ""}
+    for k, v := range want {
+        if got[k] != v {
+            t.Errorf("field %s = %q, want %q", k, got[k], v)
+        }
+    }
+}
+
+func TestParseID3v1NoTag(t *testing.T) {
+    if _, err := parseID3v1([]byte("too short")); err == nil {
+        t.Fatal("parseID3v1 on data with no TAG marker: want error, got nil")
+    }
+}
+
+func TestParseID3v2(t *testing.T) {
+    title := "Test Title"
+    frame := append([]byte{0x00}, []byte(title)...) // leading byte: text encoding
+
+    var header bytes.Buffer
+    header.WriteString("ID3")
+    header.Write([]byte{3, 0, 0}) // version 2.3, flags
+    header.Write(syncSafeBytes(uint32(10 + len(frame))))
+    header.WriteString("TIT2")
+    binary.Write(&header, binary.BigEndian, uint32(len(frame)))
+    header.Write([]byte{0, 0}) // frame flags
+    header.Write(frame)
+
+    got, err := parseID3v2(header.Bytes())
+    if err != nil {
+        t.Fatalf("parseID3v2: %v", err)
+    }
+    if got["TIT2"] != title {
+        t.Errorf("TIT2 = %q, want %q", got["TIT2"], title)
+    }
+}
+
+func syncSafeBytes(size uint32) []byte {
+    return []byte{
+        byte(size >> 21 & 0x7f),
+        byte(size >> 14 & 0x7f),
+        byte(size >> 7 & 0x7f),
+        byte(size & 0x7f),
+    }
+}
+
+func TestDecodeVorbisComment(t *testing.T) {
+    var block bytes.Buffer
+    vendor := "test-encoder"
+    binary.Write(&block, binary.LittleEndian, uint32(len(vendor)))
+    block.WriteString(vendor)
+    binary.Write(&block, binary.LittleEndian, uint32(1)) // comment count
+    comment := "ARTIST=Test Artist"
+    binary.Write(&block, binary.LittleEndian, uint32(len(comment)))
+    block.WriteString(comment)
+
+    got, err := decodeVorbisComment(block.Bytes())
+    if err != nil {
+        t.Fatalf("decodeVorbisComment: %v", err)
+    }
+    if got["VENDOR"] != vendor {
+        t.Errorf("VENDOR = %q, want %q", got["VENDOR"], vendor)
+    }
+    if got["ARTIST"] != "Test Artist" {
+        t.Errorf("ARTIST = %q, want %q", got["ARTIST"], "Test Artist")
+    }
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "bytes"
+    "debug/elf"
+    "debug/pe"
+    "fmt"
+    "io"
+    "log"
+)
+
+func init() {
+    Register(&binaryExtractor{})
+}
+
+// binaryExtractor reads header metadata out of ELF and PE executables
+// using the standard library's debug/elf and debug/pe packages.
+type binaryExtractor struct{}
+
+func (binaryExtractor) Extensions() []string { return []string{"elf", "exe", "dll", "so"} }
+func (binaryExtractor) Type() string         { return "Binary" }
+
+func (binaryExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting binary header metadata from %s\n", name)
+
+    raw, err := io.ReadAll(r)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read binary: %w", err)
+    }
+    ra := bytes.NewReader(raw)
+
+    if f, err := elf.NewFile(ra); err == nil {
+        defer f.Close()
+        sections := make([]string, 0, len(f.Sections))
+        for _, s := range f.Sections {
+            sections = append(sections, s.Name)
+        }
+        return map[string]interface{}{
+            "Format":   "ELF",
+            "Class":    f.Class.String(),
+            "Machine":  f.Machine.String(),
+            "Type":     f.Type.String(),
+            "Entry":    f.Entry,
+            "Sections": sections,
+        }, nil
+    }
+
+    if _, err := ra.Seek(0, io.SeekStart); err != nil {
+        return nil, fmt.Errorf("failed to rewind binary: %w", err)
+    }
+    if f, err := pe.NewFile(ra); err == nil {
+        defer f.Close()
+        sections := make([]string, 0, len(f.Sections))
+        for _, s := range f.Sections {
+            sections = append(sections, s.Name)
+        }
+        return map[string]interface{}{
+            "Format":           "PE",
+            "Machine":          f.Machine,
+            "NumberOfSections": f.NumberOfSections,
+            "TimeDateStamp":    f.TimeDateStamp,
+            "Characteristics":  f.Characteristics,
+            "Sections":         sections,
+        }, nil
+    }
+
+    return nil, fmt.Errorf("not a recognized ELF or PE binary")
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "log"
+    "os"
+    "path/filepath"
+)
+
+// cacheVersion is bumped whenever an extractor's output format changes in
+// a way that should invalidate previously cached results.
+const cacheVersion = 1
+
+// cacheEntry is what actually gets written under <cache-dir>/<hash>.json.
+// Wrapping the Metadata in a versioned envelope lets cacheLookup discard
+// entries written by an older build of the extractors.
+type cacheEntry struct {
+    Version  int      `json:"version"`
+    Metadata Metadata `json:"metadata"`
+}
+
+// cacheKey returns the hex-encoded SHA-256 digest of raw combined with
+// ext, used as the cache key so the same file content is only ever
+// extracted once, regardless of which archive or path it was found
+// under. ext is folded in because the same bytes decode differently
+// depending on which extractor they're fed to (e.g. a file duplicated
+// as both .json and .xml), and a bare content hash would let one
+// extension's result leak into another's cache lookup.
+func cacheKey(raw []byte, ext string) string {
+    h := sha256.New()
+    h.Write(raw)
+    h.Write([]byte{0})
+    h.Write([]byte(ext))
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheLookup returns the cached Metadata for hash, if cacheDir holds a
+// valid, current-version entry for it.
+func cacheLookup(cacheDir, hash string) (Metadata, bool) {
+    if cacheDir == "" {
+        return Metadata{}, false
+    }
+
+    raw, err := os.ReadFile(filepath.Join(cacheDir, hash+".json"))
+    if err != nil {
+        return Metadata{}, false
+    }
+
+    var entry cacheEntry
+    if err := json.Unmarshal(raw, &entry); err != nil || entry.Version != cacheVersion {
+        return Metadata{}, false
+    }
+    return entry.Metadata, true
+}
+
+// cacheStore persists metadata under cacheDir, keyed by hash. Failures to
+// write are logged and otherwise ignored; the cache is a speed
+// optimization, not a source of truth.
+func cacheStore(cacheDir, hash string, metadata Metadata) {
+    if cacheDir == "" {
+        return
+    }
+    if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+        log.Printf("Failed to create cache dir %s: %v\n", cacheDir, err)
+        return
+    }
+
+    entry := cacheEntry{Version: cacheVersion, Metadata: metadata}
+    raw, err := json.Marshal(entry)
+    if err != nil {
+        log.Printf("Failed to marshal cache entry for %s: %v\n", hash, err)
+        return
+    }
+
+    path := filepath.Join(cacheDir, hash+".json")
+    if err := os.WriteFile(path, raw, 0o644); err != nil {
+        log.Printf("Failed to write cache entry %s: %v\n", path, err)
+    }
+}
+
+// extractWithCache computes raw's content hash, serves a cached result on
+// hit, and otherwise extracts via the registered Extractor and populates
+// the cache on the way out.
+func extractWithCache(cacheDir string, raw []byte, name, ext string) (Metadata, error) {
+    hash := cacheKey(raw, ext)
+
+    if cached, ok := cacheLookup(cacheDir, hash); ok {
+        log.Printf("Cache hit for %s (%s)\n", name, hash)
+        cached.Filename = name
+        return cached, nil
+    }
+
+    metadata, err := extractMetadata(bytes.NewReader(raw), name, ext)
+    if err != nil {
+        return metadata, err
+    }
+
+    cacheStore(cacheDir, hash, metadata)
+    return metadata, nil
+}
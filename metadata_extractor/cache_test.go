@@ -0,0 +1,61 @@
+package main
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestCacheKeyDiffersByExtension(t *testing.T) {
+    raw := []byte(`{"a":1}`)
+    jsonKey := cacheKey(raw, "json")
+    xmlKey := cacheKey(raw, "xml")
+
+    if jsonKey == xmlKey {
+        t.Fatalf("cacheKey(%q, json) == cacheKey(%q, xml): %s, want different keys for different extensions", raw, raw, jsonKey)
+    }
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+    dir := t.TempDir()
+    key := cacheKey([]byte(`{"a":1}`), "json")
+    want := Metadata{Filename: "ignored-on-store", Type: "JSON", Data: map[string]interface{}{"a": float64(1)}}
+
+    cacheStore(dir, key, want)
+
+    got, ok := cacheLookup(dir, key)
+    if !ok {
+        t.Fatalf("cacheLookup(%s) after cacheStore: not found", key)
+    }
+    if got.Type != want.Type {
+        t.Errorf("cached Type = %q, want %q", got.Type, want.Type)
+    }
+}
+
+func TestCacheLookupMiss(t *testing.T) {
+    dir := t.TempDir()
+    if _, ok := cacheLookup(dir, cacheKey([]byte("nothing stored"), "json")); ok {
+        t.Fatal("cacheLookup on an empty cache dir returned a hit")
+    }
+}
+
+func TestCacheLookupRejectsStaleVersion(t *testing.T) {
+    dir := t.TempDir()
+    key := cacheKey([]byte(`{"a":1}`), "json")
+    cacheStore(dir, key, Metadata{Type: "JSON"})
+
+    // Simulate a cacheVersion bump invalidating previously written entries.
+    stale := cacheEntry{Version: cacheVersion - 1, Metadata: Metadata{Type: "JSON"}}
+    raw, err := json.Marshal(stale)
+    if err != nil {
+        t.Fatalf("marshal stale entry: %v", err)
+    }
+    if err := os.WriteFile(filepath.Join(dir, key+".json"), raw, 0o644); err != nil {
+        t.Fatalf("write stale cache entry: %v", err)
+    }
+
+    if _, ok := cacheLookup(dir, key); ok {
+        t.Fatal("cacheLookup accepted an entry with an outdated cacheVersion")
+    }
+}
@@ -0,0 +1,75 @@
+package main
+
+import "sync"
+
+// Severity classifies how serious a Diagnostic is.
+type Severity string
+
+const (
+    SeverityWarning Severity = "warning"
+    SeverityError   Severity = "error"
+)
+
+// Diagnostic records a single per-file failure so batch runs produce a
+// machine-readable summary instead of a line buried in the log file.
+type Diagnostic struct {
+    Filename string
+    Stage    string
+    Err      string
+    Severity Severity
+}
+
+// diagnosticCollector gathers Diagnostics from concurrent workers.
+type diagnosticCollector struct {
+    mu   sync.Mutex
+    list []Diagnostic
+}
+
+func (c *diagnosticCollector) add(filename, stage string, err error, severity Severity) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.list = append(c.list, Diagnostic{
+        Filename: filename,
+        Stage:    stage,
+        Err:      err.Error(),
+        Severity: severity,
+    })
+}
+
+func (c *diagnosticCollector) hasSeverity(s Severity) bool {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    for _, d := range c.list {
+        if d.Severity == s {
+            return true
+        }
+    }
+    return false
+}
+
+func (c *diagnosticCollector) diagnostics() []Diagnostic {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return append([]Diagnostic(nil), c.list...)
+}
+
+// Exit codes, per the CLI's documented contract: 0 = all ok, 2 = partial
+// (some files failed), 1 = fatal (could not run at all).
+const (
+    exitOK      = 0
+    exitFatal   = 1
+    exitPartial = 2
+)
+
+// exitCode derives the process exit code from the collected diagnostics.
+// Errors always count as a partial failure; warnings only do under
+// --strict, since that's the flag that says "don't let anything slide".
+func (c *diagnosticCollector) exitCode(strict bool) int {
+    if c.hasSeverity(SeverityError) {
+        return exitPartial
+    }
+    if strict && c.hasSeverity(SeverityWarning) {
+        return exitPartial
+    }
+    return exitOK
+}
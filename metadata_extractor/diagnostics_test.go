@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestExitCodeErrorAlwaysPartial(t *testing.T) {
+    c := &diagnosticCollector{}
+    c.add("bad.json", "extract", errString("boom"), SeverityError)
+
+    if got := c.exitCode(false); got != exitPartial {
+        t.Errorf("exitCode(false) = %d, want %d", got, exitPartial)
+    }
+    if got := c.exitCode(true); got != exitPartial {
+        t.Errorf("exitCode(true) = %d, want %d", got, exitPartial)
+    }
+}
+
+func TestExitCodeWarningOnlyPartialUnderStrict(t *testing.T) {
+    c := &diagnosticCollector{}
+    c.add("archive.7z", "archive", errString("no archive backend registered for .7z"), SeverityWarning)
+
+    if got := c.exitCode(false); got != exitOK {
+        t.Errorf("exitCode(false) = %d, want %d", got, exitOK)
+    }
+    if got := c.exitCode(true); got != exitPartial {
+        t.Errorf("exitCode(true) = %d, want %d", got, exitPartial)
+    }
+}
+
+func TestExitCodeNoDiagnosticsIsOK(t *testing.T) {
+    c := &diagnosticCollector{}
+    if got := c.exitCode(true); got != exitOK {
+        t.Errorf("exitCode(true) on empty collector = %d, want %d", got, exitOK)
+    }
+}
+
+type errString string
+
+func (e errString) Error() string { return string(e) }
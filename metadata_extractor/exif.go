@@ -0,0 +1,43 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "log"
+
+    "github.com/rwcarlsen/goexif/exif"
+    "github.com/rwcarlsen/goexif/tiff"
+)
+
+func init() {
+    Register(&exifExtractor{})
+}
+
+// exifExtractor reads EXIF tags out of common raster image formats.
+type exifExtractor struct{}
+
+func (exifExtractor) Extensions() []string { return []string{"jpg", "jpeg", "png", "tiff", "bmp"} }
+func (exifExtractor) Type() string         { return "EXIF" }
+
+func (exifExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting EXIF data from %s\n", name)
+
+    x, err := exif.Decode(r)
+    if err != nil {
+        return nil, fmt.Errorf("failed to decode exif data: %w", err)
+    }
+
+    data := make(map[string]interface{})
+    x.Walk(&exifWalker{data})
+    return data, nil
+}
+
+// exifWalker implements the exif.Walker interface
+type exifWalker struct {
+    data map[string]interface{}
+}
+
+func (w *exifWalker) Walk(name exif.FieldName, tag *tiff.Tag) error {
+    w.data[string(name)] = tag.String()
+    return nil
+}
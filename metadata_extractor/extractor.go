@@ -0,0 +1,75 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "sort"
+)
+
+// Extractor is implemented by every format-specific metadata extractor.
+// Extractors self-register via init() using Register, so adding a new
+// format (PDF, ID3, ELF, ...) never requires touching the core dispatcher.
+type Extractor interface {
+    // Extensions lists the lowercase file extensions (without the dot)
+    // this extractor handles, e.g. []string{"jpg", "jpeg", "png"}.
+    Extensions() []string
+
+    // Extract reads metadata from r. name is the original filename (or
+    // archive entry name) and is used only for logging/error messages.
+    Extract(r io.Reader, name string) (interface{}, error)
+
+    // Type is the human-readable metadata type reported in Metadata.Type,
+    // e.g. "EXIF" or "PDF".
+    Type() string
+}
+
+// registry maps a lowercase extension to the extractor that handles it.
+var registry = make(map[string]Extractor)
+
+// Register adds an extractor to the global registry, keyed by every
+// extension it reports via Extensions. It is intended to be called from
+// an init() function in each extractor's file. A later registration for
+// the same extension overwrites an earlier one.
+func Register(e Extractor) {
+    for _, ext := range e.Extensions() {
+        registry[ext] = e
+    }
+}
+
+// lookupExtractor returns the extractor registered for ext, if any.
+func lookupExtractor(ext string) (Extractor, bool) {
+    e, ok := registry[ext]
+    return e, ok
+}
+
+// supportedExtensions returns the sorted list of extensions with a
+// registered extractor, used for --strict diagnostics and help text.
+func supportedExtensions() []string {
+    exts := make([]string, 0, len(registry))
+    for ext := range registry {
+        exts = append(exts, ext)
+    }
+    sort.Strings(exts)
+    return exts
+}
+
+// extractMetadata extracts metadata from r based on ext, dispatching to
+// whichever Extractor is registered for that extension.
+func extractMetadata(r io.Reader, name, ext string) (Metadata, error) {
+    var metadata Metadata
+    metadata.Filename = name
+
+    extractor, ok := lookupExtractor(ext)
+    if !ok {
+        return metadata, fmt.Errorf("unsupported file type: %s", ext)
+    }
+
+    data, err := extractor.Extract(r, name)
+    if err != nil {
+        return metadata, fmt.Errorf("error extracting %s metadata: %w", extractor.Type(), err)
+    }
+
+    metadata.Type = extractor.Type()
+    metadata.Data = data
+    return metadata, nil
+}
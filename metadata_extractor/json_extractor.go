@@ -0,0 +1,28 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "log"
+)
+
+func init() {
+    Register(&jsonExtractor{})
+}
+
+// jsonExtractor decodes top-level key/value pairs from a JSON document.
+type jsonExtractor struct{}
+
+func (jsonExtractor) Extensions() []string { return []string{"json"} }
+func (jsonExtractor) Type() string         { return "JSON" }
+
+func (jsonExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting JSON data from %s\n", name)
+
+    var data map[string]interface{}
+    if err := json.NewDecoder(r).Decode(&data); err != nil {
+        return nil, fmt.Errorf("failed to decode json data: %w", err)
+    }
+    return data, nil
+}
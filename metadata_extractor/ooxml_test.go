@@ -0,0 +1,84 @@
+package main
+
+import (
+    "archive/zip"
+    "bytes"
+    "testing"
+)
+
+const testCoreXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <dc:title>Quarterly Report</dc:title>
+  <dc:creator>Jane Doe</dc:creator>
+  <cp:lastModifiedBy>John Smith</cp:lastModifiedBy>
+</cp:coreProperties>`
+
+const testAppXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">
+  <Application>Microsoft Office Word</Application>
+  <Pages>3</Pages>
+</Properties>`
+
+func buildTestDocx(t *testing.T) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    zw := zip.NewWriter(&buf)
+
+    for _, entry := range []struct{ name, content string }{
+        {"docProps/core.xml", testCoreXML},
+        {"docProps/app.xml", testAppXML},
+    } {
+        w, err := zw.Create(entry.name)
+        if err != nil {
+            t.Fatalf("create %s: %v", entry.name, err)
+        }
+        if _, err := w.Write([]byte(entry.content)); err != nil {
+            t.Fatalf("write %s: %v", entry.name, err)
+        }
+    }
+    if err := zw.Close(); err != nil {
+        t.Fatalf("close zip: %v", err)
+    }
+    return buf.Bytes()
+}
+
+func TestOOXMLExtractorDecodesCoreAndAppProperties(t *testing.T) {
+    raw := buildTestDocx(t)
+
+    result, err := ooxmlExtractor{}.Extract(bytes.NewReader(raw), "report.docx")
+    if err != nil {
+        t.Fatalf("Extract: %v", err)
+    }
+
+    data := result.(map[string]interface{})
+    core, ok := data["Core"].(*coreProperties)
+    if !ok {
+        t.Fatalf("data[\"Core\"] is %T, want *coreProperties", data["Core"])
+    }
+    if core.Title != "Quarterly Report" || core.Creator != "Jane Doe" {
+        t.Errorf("core properties = %+v, want Title=Quarterly Report Creator=Jane Doe", core)
+    }
+
+    app, ok := data["App"].(*appProperties)
+    if !ok {
+        t.Fatalf("data[\"App\"] is %T, want *appProperties", data["App"])
+    }
+    if app.Application != "Microsoft Office Word" || app.Pages != "3" {
+        t.Errorf("app properties = %+v, want Application=Microsoft Office Word Pages=3", app)
+    }
+}
+
+func TestOOXMLExtractorNoPropertiesParts(t *testing.T) {
+    var buf bytes.Buffer
+    zw := zip.NewWriter(&buf)
+    if _, err := zw.Create("word/document.xml"); err != nil {
+        t.Fatalf("create entry: %v", err)
+    }
+    if err := zw.Close(); err != nil {
+        t.Fatalf("close zip: %v", err)
+    }
+
+    if _, err := (ooxmlExtractor{}).Extract(bytes.NewReader(buf.Bytes()), "empty.docx"); err == nil {
+        t.Fatal("Extract on a zip with no recognized properties part: want error, got nil")
+    }
+}
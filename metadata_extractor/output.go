@@ -0,0 +1,332 @@
+package main
+
+import (
+    "bufio"
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "io"
+    "sort"
+
+    "gopkg.in/yaml.v2"
+)
+
+// resultEncoder is the streaming sink results and diagnostics are written
+// to as they're produced. --stream mode feeds it incrementally so a large
+// batch never needs to be held in memory at once; non-streaming callers
+// just feed it everything before calling Close.
+type resultEncoder interface {
+    WriteMetadata(Metadata) error
+    WriteDiagnostics([]Diagnostic) error
+    Close() error
+}
+
+// newResultEncoder builds the encoder for the requested --format. stream
+// only applies to ndjson: json/yaml always write one buffered document,
+// and csv always buffers so its header can cover every row's columns
+// (see csvEncoder), so --stream with either is rejected rather than
+// silently ignored.
+func newResultEncoder(w io.Writer, format string, stream bool) (resultEncoder, error) {
+    switch format {
+    case "", "json":
+        if stream {
+            return nil, fmt.Errorf("--stream is not supported with --format=json; it always writes one buffered document")
+        }
+        return &jsonEncoder{w: w}, nil
+    case "ndjson":
+        return &ndjsonEncoder{w: bufio.NewWriter(w), stream: stream}, nil
+    case "yaml":
+        if stream {
+            return nil, fmt.Errorf("--stream is not supported with --format=yaml; it always writes one buffered document")
+        }
+        return &yamlEncoder{w: w}, nil
+    case "csv":
+        if stream {
+            return nil, fmt.Errorf("--stream is not supported with --format=csv; its header requires every row's columns up front")
+        }
+        return &csvEncoder{w: csv.NewWriter(w)}, nil
+    case "sarif":
+        if stream {
+            return nil, fmt.Errorf("--stream is not supported with --format=sarif; it always writes one buffered log")
+        }
+        return &sarifEncoder{w: w}, nil
+    default:
+        return nil, fmt.Errorf("unsupported output format: %s", format)
+    }
+}
+
+// document is the shape written by the batch (non-streaming) encoders.
+type document struct {
+    Metadata []Metadata   `json:"metadata" yaml:"metadata"`
+    Errors   []Diagnostic `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// jsonEncoder reproduces the tool's original behavior: a single indented
+// JSON document written on Close.
+type jsonEncoder struct {
+    w   io.Writer
+    doc document
+}
+
+func (e *jsonEncoder) WriteMetadata(m Metadata) error {
+    e.doc.Metadata = append(e.doc.Metadata, m)
+    return nil
+}
+
+func (e *jsonEncoder) WriteDiagnostics(d []Diagnostic) error {
+    e.doc.Errors = append(e.doc.Errors, d...)
+    return nil
+}
+
+func (e *jsonEncoder) Close() error {
+    enc := json.NewEncoder(e.w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(e.doc)
+}
+
+// ndjsonEncoder writes one JSON object per line. With --stream it flushes
+// after every record, so a consumer piping through jq (or tailing the
+// output file) sees results as they land; without it, writes are only
+// flushed on Close, same as the other formats.
+type ndjsonEncoder struct {
+    w      *bufio.Writer
+    stream bool
+}
+
+func (e *ndjsonEncoder) WriteMetadata(m Metadata) error {
+    if err := json.NewEncoder(e.w).Encode(m); err != nil {
+        return err
+    }
+    return e.maybeFlush()
+}
+
+func (e *ndjsonEncoder) WriteDiagnostics(diags []Diagnostic) error {
+    for _, d := range diags {
+        if err := json.NewEncoder(e.w).Encode(d); err != nil {
+            return err
+        }
+    }
+    return e.maybeFlush()
+}
+
+func (e *ndjsonEncoder) maybeFlush() error {
+    if !e.stream {
+        return nil
+    }
+    return e.w.Flush()
+}
+
+func (e *ndjsonEncoder) Close() error {
+    return e.w.Flush()
+}
+
+// yamlEncoder buffers the whole run and emits a single YAML document.
+type yamlEncoder struct {
+    w   io.Writer
+    doc document
+}
+
+func (e *yamlEncoder) WriteMetadata(m Metadata) error {
+    e.doc.Metadata = append(e.doc.Metadata, m)
+    return nil
+}
+
+func (e *yamlEncoder) WriteDiagnostics(d []Diagnostic) error {
+    e.doc.Errors = append(e.doc.Errors, d...)
+    return nil
+}
+
+func (e *yamlEncoder) Close() error {
+    raw, err := yaml.Marshal(e.doc)
+    if err != nil {
+        return err
+    }
+    _, err = e.w.Write(raw)
+    return err
+}
+
+// csvEncoder flattens each Metadata's Data into dotted key paths so
+// nested extractor output still lands in one row per file. Different
+// extractors flatten to different key sets, and CSV only has one header
+// row, so rows are buffered until Close and written against the union of
+// every row's keys rather than just the first row's.
+type csvEncoder struct {
+    w    *csv.Writer
+    rows []map[string]string
+}
+
+func (e *csvEncoder) WriteMetadata(m Metadata) error {
+    flat := make(map[string]string)
+    flattenValue("", m.Data, flat)
+    flat["filename"] = m.Filename
+    flat["type"] = m.Type
+    e.rows = append(e.rows, flat)
+    return nil
+}
+
+func (e *csvEncoder) WriteDiagnostics(diags []Diagnostic) error {
+    // CSV output is a flat metadata table; diagnostics have a different
+    // shape, so they're reported separately via the log rather than bent
+    // into extra columns. consumeResults logs every diagnostic it sees.
+    return nil
+}
+
+func (e *csvEncoder) Close() error {
+    keySet := make(map[string]bool)
+    for _, row := range e.rows {
+        for k := range row {
+            keySet[k] = true
+        }
+    }
+    keys := make([]string, 0, len(keySet))
+    for k := range keySet {
+        if k != "filename" && k != "type" {
+            keys = append(keys, k)
+        }
+    }
+    sort.Strings(keys)
+    header := append([]string{"filename", "type"}, keys...)
+
+    if err := e.w.Write(header); err != nil {
+        return err
+    }
+    for _, row := range e.rows {
+        record := make([]string, len(header))
+        for i, k := range header {
+            record[i] = row[k]
+        }
+        if err := e.w.Write(record); err != nil {
+            return err
+        }
+    }
+
+    e.w.Flush()
+    return e.w.Error()
+}
+
+// sarifLog is a minimal SARIF 2.1.0 log: one run, with every extracted
+// file and every diagnostic surfaced as a result, for forensic triage
+// tooling that already knows how to ingest SARIF.
+type sarifLog struct {
+    Schema  string     `json:"$schema"`
+    Version string     `json:"version"`
+    Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+    Tool    sarifTool     `json:"tool"`
+    Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+    Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+    Name string `json:"name"`
+}
+
+type sarifResult struct {
+    RuleID    string          `json:"ruleId"`
+    Level     string          `json:"level"`
+    Message   sarifMessage    `json:"message"`
+    Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+    Text string `json:"text"`
+}
+
+type sarifLocation struct {
+    PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+    ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+    URI string `json:"uri"`
+}
+
+// sarifEncoder buffers every result into a single SARIF log, written on
+// Close; SARIF's schema has no line-delimited form to stream into.
+type sarifEncoder struct {
+    w       io.Writer
+    results []sarifResult
+}
+
+func sarifArtifactLocationFor(filename string) []sarifLocation {
+    return []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filename}}}}
+}
+
+func (e *sarifEncoder) WriteMetadata(m Metadata) error {
+    e.results = append(e.results, sarifResult{
+        RuleID:    "metadata/" + m.Type,
+        Level:     "note",
+        Message:   sarifMessage{Text: fmt.Sprintf("%s metadata extracted from %s", m.Type, m.Filename)},
+        Locations: sarifArtifactLocationFor(m.Filename),
+    })
+    return nil
+}
+
+func (e *sarifEncoder) WriteDiagnostics(diags []Diagnostic) error {
+    for _, d := range diags {
+        level := "warning"
+        if d.Severity == SeverityError {
+            level = "error"
+        }
+        e.results = append(e.results, sarifResult{
+            RuleID:    "extraction/" + d.Stage,
+            Level:     level,
+            Message:   sarifMessage{Text: d.Err},
+            Locations: sarifArtifactLocationFor(d.Filename),
+        })
+    }
+    return nil
+}
+
+func (e *sarifEncoder) Close() error {
+    log := sarifLog{
+        Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+        Version: "2.1.0",
+        Runs: []sarifRun{{
+            Tool:    sarifTool{Driver: sarifDriver{Name: "metadata-extractor"}},
+            Results: e.results,
+        }},
+    }
+
+    enc := json.NewEncoder(e.w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(log)
+}
+
+// flattenValue walks an arbitrary extractor result, recording leaf values
+// under their dotted key path (e.g. "Fields.Author").
+func flattenValue(prefix string, v interface{}, out map[string]string) {
+    switch val := v.(type) {
+    case map[string]interface{}:
+        for k, child := range val {
+            flattenValue(joinPath(prefix, k), child, out)
+        }
+    case map[interface{}]interface{}:
+        // yaml.v2 decodes untyped nested mappings into this shape rather
+        // than map[string]interface{}.
+        for k, child := range val {
+            flattenValue(joinPath(prefix, fmt.Sprintf("%v", k)), child, out)
+        }
+    case []interface{}:
+        for i, child := range val {
+            flattenValue(fmt.Sprintf("%s[%d]", prefix, i), child, out)
+        }
+    default:
+        out[prefix] = fmt.Sprintf("%v", val)
+    }
+}
+
+func joinPath(prefix, key string) string {
+    if prefix == "" {
+        return key
+    }
+    return prefix + "." + key
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "testing"
+)
+
+func TestSarifEncoderResults(t *testing.T) {
+    var buf bytes.Buffer
+    enc, err := newResultEncoder(&buf, "sarif", false)
+    if err != nil {
+        t.Fatalf("newResultEncoder: %v", err)
+    }
+
+    if err := enc.WriteMetadata(Metadata{Filename: "photo.jpg", Type: "EXIF", Data: map[string]interface{}{"Make": "Nikon"}}); err != nil {
+        t.Fatalf("WriteMetadata: %v", err)
+    }
+    if err := enc.WriteDiagnostics([]Diagnostic{{Filename: "bad.json", Stage: "extract", Err: "boom", Severity: SeverityError}}); err != nil {
+        t.Fatalf("WriteDiagnostics: %v", err)
+    }
+    if err := enc.Close(); err != nil {
+        t.Fatalf("Close: %v", err)
+    }
+
+    var log sarifLog
+    if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+        t.Fatalf("output is not valid JSON: %v", err)
+    }
+    if log.Version != "2.1.0" || len(log.Runs) != 1 {
+        t.Fatalf("log = %+v, want version 2.1.0 with one run", log)
+    }
+
+    results := log.Runs[0].Results
+    if len(results) != 2 {
+        t.Fatalf("got %d results, want 2", len(results))
+    }
+    if results[0].RuleID != "metadata/EXIF" || results[0].Level != "note" {
+        t.Errorf("metadata result = %+v, want ruleId metadata/EXIF, level note", results[0])
+    }
+    if results[1].RuleID != "extraction/extract" || results[1].Level != "error" {
+        t.Errorf("diagnostic result = %+v, want ruleId extraction/extract, level error", results[1])
+    }
+}
+
+func TestSarifRejectsStream(t *testing.T) {
+    if _, err := newResultEncoder(&bytes.Buffer{}, "sarif", true); err == nil {
+        t.Fatal("newResultEncoder(sarif, stream=true): want error, got nil")
+    }
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "log"
+    "regexp"
+)
+
+func init() {
+    Register(&pdfExtractor{})
+}
+
+// pdfExtractor pulls the Info dictionary and, when present, the embedded
+// XMP packet out of a PDF. It works directly on the raw bytes rather than
+// building a full object-graph parser, which is enough to recover the
+// handful of fields users actually care about (Title, Author, Producer,
+// dates, ...).
+type pdfExtractor struct{}
+
+func (pdfExtractor) Extensions() []string { return []string{"pdf"} }
+func (pdfExtractor) Type() string         { return "PDF" }
+
+// infoKeyPattern matches "/Key (literal value)" entries inside the Info
+// dictionary, e.g. "/Author (Jane Doe)".
+var infoKeyPattern = regexp.MustCompile(`/(Title|Author|Subject|Keywords|Creator|Producer|CreationDate|ModDate)\s*\(([^)]*)\)`)
+
+// xmpPacketPattern extracts the whole embedded XMP packet, if any.
+var xmpPacketPattern = regexp.MustCompile(`(?s)<\?xpacket begin.*?<\?xpacket end="w"\?>`)
+
+func (pdfExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting PDF metadata from %s\n", name)
+
+    raw, err := io.ReadAll(r)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read pdf: %w", err)
+    }
+
+    data := make(map[string]interface{})
+    for _, match := range infoKeyPattern.FindAllSubmatch(raw, -1) {
+        data[string(match[1])] = string(match[2])
+    }
+
+    if xmp := xmpPacketPattern.Find(raw); xmp != nil {
+        data["XMP"] = string(xmp)
+    }
+
+    if len(data) == 0 {
+        return nil, fmt.Errorf("no Info dictionary or XMP packet found")
+    }
+    return data, nil
+}
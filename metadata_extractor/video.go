@@ -0,0 +1,125 @@
+package main
+
+import (
+    "encoding/binary"
+    "fmt"
+    "io"
+    "log"
+)
+
+func init() {
+    Register(&videoExtractor{})
+}
+
+// videoExtractor walks the container structure of MP4/MOV (ISO BMFF box
+// layout) and Matroska/WebM (EBML) files and reports the top-level
+// elements it finds. Full tag extraction (iTunes-style ilst atoms,
+// Matroska Tags elements) is left for a follow-up; this gives callers
+// the container skeleton today.
+type videoExtractor struct{}
+
+func (videoExtractor) Extensions() []string { return []string{"mp4", "mov", "m4v", "mkv", "webm"} }
+func (videoExtractor) Type() string         { return "Video" }
+
+func (videoExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting video container metadata from %s\n", name)
+
+    raw, err := io.ReadAll(r)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read video file: %w", err)
+    }
+
+    if len(raw) >= 4 && raw[0] == 0x1A && raw[1] == 0x45 && raw[2] == 0xDF && raw[3] == 0xA3 {
+        return parseEBMLElements(raw)
+    }
+    return parseMP4Boxes(raw)
+}
+
+// parseMP4Boxes walks the top-level ISO BMFF boxes (ftyp, moov, mdat, ...)
+// and reports each one's type and size.
+func parseMP4Boxes(raw []byte) (interface{}, error) {
+    var boxes []map[string]interface{}
+    pos := 0
+    for pos+8 <= len(raw) {
+        size := int(binary.BigEndian.Uint32(raw[pos : pos+4]))
+        boxType := string(raw[pos+4 : pos+8])
+        if size == 0 {
+            size = len(raw) - pos
+        }
+        if size < 8 || pos+size > len(raw) {
+            break
+        }
+        boxes = append(boxes, map[string]interface{}{"type": boxType, "size": size})
+        pos += size
+    }
+
+    if len(boxes) == 0 {
+        return nil, fmt.Errorf("no ISO BMFF boxes found")
+    }
+    return map[string]interface{}{"boxes": boxes}, nil
+}
+
+// parseEBMLElements walks the top-level EBML elements of a Matroska/WebM
+// file (EBML header, Segment, ...) using minimal vint decoding.
+func parseEBMLElements(raw []byte) (interface{}, error) {
+    var elements []map[string]interface{}
+    pos := 0
+    for pos < len(raw) {
+        id, idLen, ok := readEBMLVint(raw[pos:], true)
+        if !ok {
+            break
+        }
+        pos += idLen
+
+        size, sizeLen, ok := readEBMLVint(raw[pos:], false)
+        if !ok {
+            break
+        }
+        pos += sizeLen
+
+        elements = append(elements, map[string]interface{}{
+            "id":   fmt.Sprintf("0x%X", id),
+            "size": size,
+        })
+
+        if size < 0 || pos+int(size) > len(raw) {
+            break
+        }
+        pos += int(size)
+    }
+
+    if len(elements) == 0 {
+        return nil, fmt.Errorf("no EBML elements found")
+    }
+    return map[string]interface{}{"elements": elements}, nil
+}
+
+// readEBMLVint decodes an EBML variable-length integer. When keepMarker
+// is true (element IDs), the leading length-marker bits are kept as part
+// of the value; otherwise (element sizes) they are masked off.
+func readEBMLVint(b []byte, keepMarker bool) (value int64, length int, ok bool) {
+    if len(b) == 0 {
+        return 0, 0, false
+    }
+    first := b[0]
+    length = 1
+    mask := byte(0x80)
+    for mask != 0 && first&mask == 0 {
+        mask >>= 1
+        length++
+    }
+    if length > 8 || length > len(b) {
+        return 0, 0, false
+    }
+
+    var v int64
+    if keepMarker {
+        v = int64(b[0])
+    } else {
+        v = int64(b[0] &^ mask)
+    }
+    for i := 1; i < length; i++ {
+        v = v<<8 | int64(b[i])
+    }
+    return v, length, true
+}
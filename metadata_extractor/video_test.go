@@ -0,0 +1,68 @@
+package main
+
+import (
+    "encoding/binary"
+    "testing"
+)
+
+func mp4Box(boxType string, payload []byte) []byte {
+    box := make([]byte, 8+len(payload))
+    binary.BigEndian.PutUint32(box[0:4], uint32(len(box)))
+    copy(box[4:8], boxType)
+    copy(box[8:], payload)
+    return box
+}
+
+func TestParseMP4Boxes(t *testing.T) {
+    raw := append(mp4Box("ftyp", []byte("isom")), mp4Box("moov", []byte{1, 2, 3, 4})...)
+
+    result, err := parseMP4Boxes(raw)
+    if err != nil {
+        t.Fatalf("parseMP4Boxes: %v", err)
+    }
+    boxes := result.(map[string]interface{})["boxes"].([]map[string]interface{})
+    if len(boxes) != 2 {
+        t.Fatalf("got %d boxes, want 2", len(boxes))
+    }
+    if boxes[0]["type"] != "ftyp" || boxes[1]["type"] != "moov" {
+        t.Errorf("got box types %v, %v; want ftyp, moov", boxes[0]["type"], boxes[1]["type"])
+    }
+}
+
+func TestParseMP4BoxesTruncated(t *testing.T) {
+    if _, err := parseMP4Boxes([]byte{0, 0}); err == nil {
+        t.Fatal("parseMP4Boxes on truncated input: want error, got nil")
+    }
+}
+
+func TestReadEBMLVint(t *testing.T) {
+    cases := []struct {
+        name       string
+        b          []byte
+        keepMarker bool
+        wantValue  int64
+        wantLen    int
+    }{
+        {"1-byte size", []byte{0x82}, false, 2, 1},
+        {"2-byte size", []byte{0x41, 0x00}, false, 256, 2},
+        {"1-byte id keeps marker", []byte{0x83}, true, 0x83, 1},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            value, length, ok := readEBMLVint(c.b, c.keepMarker)
+            if !ok {
+                t.Fatalf("readEBMLVint(%x): not ok", c.b)
+            }
+            if value != c.wantValue || length != c.wantLen {
+                t.Errorf("readEBMLVint(%x) = (%d, %d), want (%d, %d)", c.b, value, length, c.wantValue, c.wantLen)
+            }
+        })
+    }
+}
+
+func TestReadEBMLVintEmpty(t *testing.T) {
+    if _, _, ok := readEBMLVint(nil, false); ok {
+        t.Fatal("readEBMLVint on empty input: want not ok")
+    }
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "context"
+    "log"
+    "sync"
+)
+
+// job is a unit of work fed to the worker pool: a file's (or archive
+// entry's) raw bytes, name and extension.
+type job struct {
+    Name string
+    Ext  string
+    Raw  []byte
+}
+
+// extractionOptions are the flags that affect how a job is processed,
+// independent of where it came from (a bare file, a ZIP entry, ...).
+type extractionOptions struct {
+    CacheDir string
+    Strict   bool
+    FailFast bool
+}
+
+// workerPool extracts metadata from a stream of jobs using a bounded
+// number of goroutines, matching the --workers flag (default
+// runtime.GOMAXPROCS). Results and diagnostics are delivered on separate
+// channels so a single consumer goroutine can serialize them into the
+// output encoder without a shared lock.
+type workerPool struct {
+    jobs    chan job
+    results chan Metadata
+    diags   chan Diagnostic
+    opts    extractionOptions
+
+    ctx    context.Context
+    cancel context.CancelFunc
+    wg     sync.WaitGroup
+}
+
+func newWorkerPool(workers int, opts extractionOptions) *workerPool {
+    if workers < 1 {
+        workers = 1
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    p := &workerPool{
+        jobs:    make(chan job, workers),
+        results: make(chan Metadata, workers),
+        diags:   make(chan Diagnostic, workers),
+        opts:    opts,
+        ctx:     ctx,
+        cancel:  cancel,
+    }
+
+    p.wg.Add(workers)
+    for i := 0; i < workers; i++ {
+        go p.work()
+    }
+    return p
+}
+
+func (p *workerPool) work() {
+    defer p.wg.Done()
+    for {
+        select {
+        case <-p.ctx.Done():
+            return
+        case j, ok := <-p.jobs:
+            if !ok {
+                return
+            }
+            p.process(j)
+        }
+    }
+}
+
+func (p *workerPool) process(j job) {
+    if _, ok := lookupExtractor(j.Ext); !ok && !p.opts.Strict {
+        log.Printf("Skipping unsupported file type %s for %s\n", j.Ext, j.Name)
+        return
+    }
+
+    metadata, err := extractWithCache(p.opts.CacheDir, j.Raw, j.Name, j.Ext)
+    if err != nil {
+        p.diags <- Diagnostic{Filename: j.Name, Stage: "extract", Err: err.Error(), Severity: SeverityError}
+        if p.opts.FailFast {
+            p.cancel()
+        }
+        return
+    }
+    p.results <- metadata
+}
+
+// submit enqueues j, returning false if the pool has already been
+// cancelled (fail-fast tripped).
+func (p *workerPool) submit(j job) bool {
+    select {
+    case <-p.ctx.Done():
+        return false
+    case p.jobs <- j:
+        return true
+    }
+}
+
+// closeAndWait closes the jobs channel, waits for all workers to drain
+// it, then closes the output channels.
+func (p *workerPool) closeAndWait() {
+    close(p.jobs)
+    p.wg.Wait()
+    close(p.results)
+    close(p.diags)
+}
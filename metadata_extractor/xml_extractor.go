@@ -0,0 +1,28 @@
+package main
+
+import (
+    "encoding/xml"
+    "fmt"
+    "io"
+    "log"
+)
+
+func init() {
+    Register(&xmlExtractor{})
+}
+
+// xmlExtractor decodes top-level key/value pairs from an XML document.
+type xmlExtractor struct{}
+
+func (xmlExtractor) Extensions() []string { return []string{"xml"} }
+func (xmlExtractor) Type() string         { return "XML" }
+
+func (xmlExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting XML data from %s\n", name)
+
+    var data map[string]interface{}
+    if err := xml.NewDecoder(r).Decode(&data); err != nil {
+        return nil, fmt.Errorf("failed to decode xml data: %w", err)
+    }
+    return data, nil
+}
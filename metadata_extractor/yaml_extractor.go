@@ -0,0 +1,29 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "log"
+
+    "gopkg.in/yaml.v2"
+)
+
+func init() {
+    Register(&yamlExtractor{})
+}
+
+// yamlExtractor decodes top-level key/value pairs from a YAML document.
+type yamlExtractor struct{}
+
+func (yamlExtractor) Extensions() []string { return []string{"yaml", "yml"} }
+func (yamlExtractor) Type() string         { return "YAML" }
+
+func (yamlExtractor) Extract(r io.Reader, name string) (interface{}, error) {
+    log.Printf("Extracting YAML data from %s\n", name)
+
+    var data map[string]interface{}
+    if err := yaml.NewDecoder(r).Decode(&data); err != nil {
+        return nil, fmt.Errorf("failed to decode yaml data: %w", err)
+    }
+    return data, nil
+}